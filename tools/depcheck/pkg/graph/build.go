@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildGraphOptions controls which of a package's import sets
+// BuildGraphWithOptions draws edges from.
+type BuildGraphOptions struct {
+	// IncludeTests adds edges for imports that only appear in a
+	// package's own test files (Package.TestImports).
+	IncludeTests bool
+	// IncludeXTests adds edges for imports that only appear in a
+	// package's external test files (Package.XTestImports).
+	IncludeXTests bool
+}
+
+// BuildGraph constructs a dependency Graph from pkgs using only
+// production (non-test) imports. It is equivalent to calling
+// BuildGraphWithOptions with a zero-value BuildGraphOptions.
+//
+// mains, if non-empty, lists the import paths that are expected to act as
+// entrypoints for the graph (e.g. the repo's own packages as opposed to its
+// dependencies). Each one must correspond to a node in the resulting graph
+// or BuildGraph returns an error.
+//
+// excludes lists import paths that should be omitted from the graph
+// entirely: no node is created for them, and no edges point to them.
+func BuildGraph(pkgs *PackageList, mains []string, excludes []string) (*Graph, error) {
+	return BuildGraphWithOptions(pkgs, mains, excludes, BuildGraphOptions{})
+}
+
+// BuildGraphWithOptions is BuildGraph with control over whether test-only
+// imports participate in the graph. See BuildGraphOptions.
+func BuildGraphWithOptions(pkgs *PackageList, mains []string, excludes []string, opts BuildGraphOptions) (*Graph, error) {
+	excluded := make(map[string]bool, len(excludes))
+	for _, e := range excludes {
+		excluded[e] = true
+	}
+
+	g := newGraph()
+
+	for _, pkg := range pkgs.Packages {
+		if !isValidImportPath(pkg.ImportPath) {
+			continue
+		}
+		if excluded[pkg.ImportPath] {
+			continue
+		}
+
+		g.addNode(&Node{
+			UniqueName: pkg.ImportPath,
+			LabelName:  labelFor(pkg.ImportPath),
+		})
+	}
+
+	for _, main := range mains {
+		if _, exists := g.NodeByName(main); !exists {
+			return nil, fmt.Errorf("no corresponding node found for the root name %q", main)
+		}
+	}
+	g.roots = append([]string(nil), mains...)
+
+	byDir := make(map[string]string, len(pkgs.Packages))
+	for _, pkg := range pkgs.Packages {
+		if _, exists := g.NodeByName(pkg.ImportPath); exists {
+			byDir[pkg.Dir] = pkg.ImportPath
+		}
+	}
+
+	for _, pkg := range pkgs.Packages {
+		if _, exists := g.NodeByName(pkg.ImportPath); !exists {
+			continue
+		}
+
+		addEdges(g, byDir, excluded, pkg, pkg.Imports, ImportNormal)
+		if opts.IncludeTests {
+			addEdges(g, byDir, excluded, pkg, pkg.TestImports, ImportTest)
+		}
+		if opts.IncludeXTests {
+			addEdges(g, byDir, excluded, pkg, pkg.XTestImports, ImportXTest)
+		}
+	}
+
+	return g, nil
+}
+
+// addEdges resolves and records an edge of the given kind from pkg to each
+// import in imports, skipping standard library imports, excluded import
+// paths, and imports that don't correspond to a node in g.
+func addEdges(g *Graph, byDir map[string]string, excluded map[string]bool, pkg Package, imports []string, kind EdgeKind) {
+	for _, dep := range imports {
+		resolved := resolveImport(byDir, pkg.Dir, dep)
+		if resolved == dep && isStandardLibrary(dep) {
+			continue
+		}
+		if excluded[resolved] {
+			continue
+		}
+		if _, exists := g.NodeByName(resolved); !exists {
+			continue
+		}
+		g.addEdge(pkg.ImportPath, resolved, kind)
+	}
+}
+
+// isValidImportPath reports whether importPath could plausibly be a real
+// Go import path: non-empty, with no whitespace, and with no empty path
+// element (a leading, trailing, or doubled "/").
+//
+// This deliberately does not compare against Package.Dir: in module mode
+// Dir routinely diverges from ImportPath (a module cache entry like
+// ".../pkg/mod/github.com/foo/bar@v1.2.3/baz" for ImportPath
+// "github.com/foo/bar/baz", or a main module checked out outside
+// $GOPATH/src), so requiring Dir to mirror ImportPath would drop valid
+// packages rather than catching malformed ones.
+func isValidImportPath(importPath string) bool {
+	if importPath == "" {
+		return false
+	}
+	if strings.ContainsAny(importPath, " \t\n") {
+		return false
+	}
+	if strings.HasPrefix(importPath, "/") || strings.HasSuffix(importPath, "/") || strings.Contains(importPath, "//") {
+		return false
+	}
+	return true
+}
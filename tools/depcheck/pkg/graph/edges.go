@@ -0,0 +1,37 @@
+package graph
+
+// EdgeKind classifies why an edge exists between two packages.
+type EdgeKind int
+
+const (
+	// ImportNormal is an edge from a package's own (non-test) imports.
+	ImportNormal EdgeKind = iota
+	// ImportTest is an edge that only exists because of an import in one
+	// of the package's own test files (Package.TestImports).
+	ImportTest
+	// ImportXTest is an edge that only exists because of an import in
+	// one of the package's external test files, i.e. "pkg_test" files
+	// (Package.XTestImports).
+	ImportXTest
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case ImportNormal:
+		return "normal"
+	case ImportTest:
+		return "test"
+	case ImportXTest:
+		return "xtest"
+	default:
+		return "unknown"
+	}
+}
+
+// Edge is a directed dependency between two nodes, identified by their
+// unique names.
+type Edge struct {
+	From string
+	To   string
+	Kind EdgeKind
+}
@@ -0,0 +1,100 @@
+package graph
+
+import "testing"
+
+func edgeKindFixture() *PackageList {
+	return &PackageList{
+		Packages: []Package{
+			{
+				Dir:         "/path/to/github.com/test/repo/root",
+				ImportPath:  "github.com/test/repo/root",
+				Imports:     []string{"github.com/test/repo/a"},
+				TestImports: []string{"github.com/test/repo/testonly"},
+			},
+			{
+				Dir:          "/path/to/github.com/test/repo/a",
+				ImportPath:   "github.com/test/repo/a",
+				Imports:      []string{},
+				XTestImports: []string{"github.com/test/repo/xtestonly"},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/testonly",
+				ImportPath: "github.com/test/repo/testonly",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/xtestonly",
+				ImportPath: "github.com/test/repo/xtestonly",
+				Imports:    []string{},
+			},
+		},
+	}
+}
+
+func TestBuildGraphOmitsTestImportsByDefault(t *testing.T) {
+	g, err := BuildGraph(edgeKindFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root, _ := g.NodeByName("github.com/test/repo/root")
+	a, _ := g.NodeByName("github.com/test/repo/a")
+	testonly, _ := g.NodeByName("github.com/test/repo/testonly")
+	xtestonly, _ := g.NodeByName("github.com/test/repo/xtestonly")
+
+	if g.HasEdgeFromTo(root, testonly) {
+		t.Fatalf("did not expect a test-only import to produce an edge without BuildGraphOptions.IncludeTests")
+	}
+	if g.HasEdgeFromTo(a, xtestonly) {
+		t.Fatalf("did not expect an xtest-only import to produce an edge without BuildGraphOptions.IncludeXTests")
+	}
+	if len(g.EdgesByKind(ImportTest)) != 0 || len(g.EdgesByKind(ImportXTest)) != 0 {
+		t.Fatalf("did not expect any test or xtest edges without BuildGraphOptions set")
+	}
+}
+
+func TestBuildGraphWithOptionsTracksEdgeKinds(t *testing.T) {
+	g, err := BuildGraphWithOptions(edgeKindFixture(), nil, nil, BuildGraphOptions{
+		IncludeTests:  true,
+		IncludeXTests: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root, _ := g.NodeByName("github.com/test/repo/root")
+	a, _ := g.NodeByName("github.com/test/repo/a")
+	testonly, exists := g.NodeByName("github.com/test/repo/testonly")
+	if !exists {
+		t.Fatalf("expected a node for the test-only import once IncludeTests is set")
+	}
+	xtestonly, exists := g.NodeByName("github.com/test/repo/xtestonly")
+	if !exists {
+		t.Fatalf("expected a node for the xtest-only import once IncludeXTests is set")
+	}
+
+	if !g.HasEdgeFromTo(root, a) {
+		t.Fatalf("expected a normal edge from root to a")
+	}
+	if !g.HasEdgeFromTo(root, testonly) {
+		t.Fatalf("expected a test edge from root to testonly")
+	}
+	if !g.HasEdgeFromTo(a, xtestonly) {
+		t.Fatalf("expected an xtest edge from a to xtestonly")
+	}
+
+	normalEdges := g.EdgesByKind(ImportNormal)
+	if len(normalEdges) != 1 || normalEdges[0].From != root.Name() || normalEdges[0].To != a.Name() {
+		t.Fatalf("unexpected normal edges: %+v", normalEdges)
+	}
+
+	testEdges := g.EdgesByKind(ImportTest)
+	if len(testEdges) != 1 || testEdges[0].To != testonly.Name() {
+		t.Fatalf("unexpected test edges: %+v", testEdges)
+	}
+
+	xtestEdges := g.EdgesByKind(ImportXTest)
+	if len(xtestEdges) != 1 || xtestEdges[0].To != xtestonly.Name() {
+		t.Fatalf("unexpected xtest edges: %+v", xtestEdges)
+	}
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// vendorSegment is the path component Go's vendoring mechanism inserts
+// between a module root and a vendored dependency, e.g.
+// "github.com/test/repo/vendor/github.com/testvendor/vendor_one".
+const vendorSegment = "/vendor/"
+
+// GraphNode is implemented by every vertex held by a Graph. It lets callers
+// work with nodes returned from Graph without depending on the concrete
+// Node type.
+type GraphNode interface {
+	Name() string
+}
+
+// Node is a single package vertex in a dependency Graph.
+type Node struct {
+	// UniqueName is the node's full import path and is how the graph
+	// identifies it internally (node lookups, edges, etc).
+	UniqueName string
+	// LabelName is the human-facing name for the node. For vendored
+	// packages this strips the leading "<root>/vendor/" prefix so that
+	// vendored and non-vendored copies of the same package render
+	// identically.
+	LabelName string
+	// Members holds the original nodes collapsed into this one. It is
+	// nil for an ordinary node and populated only for a synthetic node
+	// produced by Graph.Condensation.
+	Members []*Node
+}
+
+// Name returns the node's unique identifier within the graph.
+func (n *Node) Name() string {
+	return n.UniqueName
+}
+
+// Graph is a directed dependency graph between packages.
+type Graph struct {
+	nodes map[string]*Node
+	order []string
+	out   map[string]map[string]EdgeKind
+	// roots holds the import paths passed to BuildGraph as entrypoints,
+	// used by APIs like ReachMap that need to distinguish a repo's own
+	// packages from the rest of the graph.
+	roots []string
+}
+
+// newGraph returns an empty Graph ready for nodes and edges to be added.
+func newGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]*Node),
+		out:   make(map[string]map[string]EdgeKind),
+	}
+}
+
+// addNode registers n with the graph if it isn't already present.
+func (g *Graph) addNode(n *Node) {
+	if _, exists := g.nodes[n.UniqueName]; exists {
+		return
+	}
+	g.nodes[n.UniqueName] = n
+	g.order = append(g.order, n.UniqueName)
+	g.out[n.UniqueName] = make(map[string]EdgeKind)
+}
+
+// addEdge records a directed edge between two nodes already present in the
+// graph. It is a no-op if either endpoint hasn't been added or an edge
+// between them already exists (the first kind recorded for a pair wins).
+func (g *Graph) addEdge(from, to string, kind EdgeKind) {
+	if _, ok := g.nodes[from]; !ok {
+		return
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return
+	}
+	if _, exists := g.out[from][to]; exists {
+		return
+	}
+	g.out[from][to] = kind
+}
+
+// Nodes returns every node in the graph, in the order they were added.
+func (g *Graph) Nodes() []GraphNode {
+	nodes := make([]GraphNode, 0, len(g.order))
+	for _, name := range g.order {
+		nodes = append(nodes, g.nodes[name])
+	}
+	return nodes
+}
+
+// NodeByName looks up a node by its unique name.
+func (g *Graph) NodeByName(name string) (*Node, bool) {
+	n, ok := g.nodes[name]
+	return n, ok
+}
+
+// Has reports whether n is a node in the graph.
+func (g *Graph) Has(n GraphNode) bool {
+	if n == nil {
+		return false
+	}
+	_, ok := g.nodes[n.Name()]
+	return ok
+}
+
+// HasEdgeFromTo reports whether there is a directed edge from -> to.
+func (g *Graph) HasEdgeFromTo(from, to GraphNode) bool {
+	if from == nil || to == nil {
+		return false
+	}
+	tos, ok := g.out[from.Name()]
+	if !ok {
+		return false
+	}
+	_, ok = tos[to.Name()]
+	return ok
+}
+
+// EdgesByKind returns every edge of the given kind in the graph, sorted by
+// (From, To) for deterministic output.
+func (g *Graph) EdgesByKind(kind EdgeKind) []Edge {
+	var edges []Edge
+	for _, from := range g.order {
+		for to, k := range g.out[from] {
+			if k == kind {
+				edges = append(edges, Edge{From: from, To: to, Kind: kind})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// labelFor derives the human-facing label for an import path, stripping any
+// leading vendor prefix so that vendored and non-vendored copies of the same
+// package share a label.
+func labelFor(importPath string) string {
+	if idx := strings.LastIndex(importPath, vendorSegment); idx != -1 {
+		return importPath[idx+len(vendorSegment):]
+	}
+	return importPath
+}
+
+// isStandardLibrary reports whether importPath looks like a standard
+// library import, using the same heuristic as the go tool itself: a
+// non-standard import path's first element contains a dot (it's a domain
+// name).
+func isStandardLibrary(importPath string) bool {
+	first := importPath
+	if idx := strings.IndexByte(importPath, '/'); idx != -1 {
+		first = importPath[:idx]
+	}
+	return !strings.Contains(first, ".")
+}
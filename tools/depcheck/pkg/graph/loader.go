@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Config controls how Load invokes the underlying package loader.
+type Config struct {
+	// BuildFlags are passed through to the build system, e.g.
+	// []string{"-tags=integration"}.
+	BuildFlags []string
+	// Env, if non-nil, overrides the process environment used while
+	// loading packages.
+	Env []string
+	// Tests, if true, also loads the test variants of the requested
+	// packages, populating their TestImports and XTestImports.
+	Tests bool
+}
+
+// Load loads the packages matching patterns (in the style accepted by `go
+// build`/`go list`, e.g. "./...") and returns them as a PackageList ready
+// for BuildGraph.
+//
+// Load never aborts because an individual package failed to load; any
+// errors are recorded on that package's Errors field instead.
+func (c Config) Load(ctx context.Context, patterns ...string) (*PackageList, error) {
+	cfg := &packages.Config{
+		Context:    ctx,
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		BuildFlags: c.BuildFlags,
+		Env:        c.Env,
+		Tests:      c.Tests,
+	}
+
+	loaded, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	return buildPackageList(loaded), nil
+}
+
+// LoadPackages loads the packages matching patterns using a zero-value
+// Config. It is shorthand for Config{}.Load(ctx, patterns...), so that
+// callers don't have to shell out to `go list -json` themselves and
+// marshal the result into a PackageList.
+func LoadPackages(ctx context.Context, patterns ...string) (*PackageList, error) {
+	return Config{}.Load(ctx, patterns...)
+}
+
+// buildPackageList turns the flat list returned by packages.Load into a
+// PackageList, folding each package's test variants (present when Tests
+// is set) back into that package's TestImports and XTestImports rather
+// than surfacing them as separate nodes.
+//
+// packages.Load represents a tested package "p" as up to three entries:
+// the plain package (ID == PkgPath), an internal test variant
+// ("p [p.test]", same PkgPath as the plain package, recompiled with its
+// own _test.go files), and an external test package ("p_test [p.test]",
+// PkgPath "p_test"). A fourth entry, the synthetic "p.test" test binary
+// itself, is discarded.
+func buildPackageList(loaded []*packages.Package) *PackageList {
+	plain := make(map[string]*packages.Package, len(loaded))
+	for _, pkg := range loaded {
+		if pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		if pkg.ID == pkg.PkgPath {
+			plain[pkg.PkgPath] = pkg
+		}
+	}
+
+	testImports := make(map[string][]string, len(plain))
+	xtestImports := make(map[string][]string, len(plain))
+
+	for _, pkg := range loaded {
+		if pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		if pkg.ID == pkg.PkgPath {
+			continue
+		}
+
+		if strings.HasSuffix(pkg.PkgPath, "_test") {
+			original := strings.TrimSuffix(pkg.PkgPath, "_test")
+			if base, ok := plain[original]; ok {
+				xtestImports[original] = importsOnlyIn(pkg.Imports, base.Imports)
+			}
+			continue
+		}
+
+		if base, ok := plain[pkg.PkgPath]; ok {
+			testImports[pkg.PkgPath] = importsOnlyIn(pkg.Imports, base.Imports)
+		}
+	}
+
+	list := &PackageList{Packages: make([]Package, 0, len(plain))}
+	for pkgPath, pkg := range plain {
+		out := packageFromLoaded(pkg)
+		out.TestImports = testImports[pkgPath]
+		out.XTestImports = xtestImports[pkgPath]
+		list.Packages = append(list.Packages, out)
+	}
+
+	sort.Slice(list.Packages, func(i, j int) bool {
+		return list.Packages[i].ImportPath < list.Packages[j].ImportPath
+	})
+
+	return list
+}
+
+// packageFromLoaded converts a *packages.Package into our own Package
+// shape, carrying over any load errors instead of dropping the package.
+func packageFromLoaded(pkg *packages.Package) Package {
+	out := Package{
+		ImportPath: pkg.PkgPath,
+		Imports:    importPathsOf(pkg.Imports),
+	}
+
+	if len(pkg.GoFiles) > 0 {
+		out.Dir = filepath.Dir(pkg.GoFiles[0])
+	}
+
+	if pkg.Module != nil {
+		out.Module = &Module{
+			Path:    pkg.Module.Path,
+			Version: pkg.Module.Version,
+			Main:    pkg.Module.Main,
+		}
+	}
+
+	for _, e := range pkg.Errors {
+		out.Errors = append(out.Errors, e.Error())
+	}
+
+	return out
+}
+
+// importPathsOf returns the sorted import paths of imports.
+func importPathsOf(imports map[string]*packages.Package) []string {
+	out := make([]string, 0, len(imports))
+	for path := range imports {
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// importsOnlyIn returns the sorted import paths present in with but not in
+// without.
+func importsOnlyIn(with, without map[string]*packages.Package) []string {
+	var out []string
+	for path := range with {
+		if _, ok := without[path]; !ok {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
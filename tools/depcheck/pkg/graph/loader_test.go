@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPackageFromLoadedCarriesErrorsInsteadOfDropping(t *testing.T) {
+	dep := &packages.Package{PkgPath: "github.com/test/repo/dep"}
+
+	pkg := &packages.Package{
+		PkgPath: "github.com/test/repo/broken",
+		GoFiles: []string{"/path/to/github.com/test/repo/broken/broken.go"},
+		Imports: map[string]*packages.Package{
+			"github.com/test/repo/dep": dep,
+		},
+		Module: &packages.Module{Path: "github.com/test/repo", Version: "v1.2.3"},
+		Errors: []packages.Error{
+			{Msg: "undeclared name: Foo"},
+		},
+	}
+
+	out := packageFromLoaded(pkg)
+
+	if out.ImportPath != "github.com/test/repo/broken" {
+		t.Fatalf("unexpected ImportPath: %v", out.ImportPath)
+	}
+	if out.Dir != "/path/to/github.com/test/repo/broken" {
+		t.Fatalf("unexpected Dir: %v", out.Dir)
+	}
+	if len(out.Imports) != 1 || out.Imports[0] != "github.com/test/repo/dep" {
+		t.Fatalf("unexpected Imports: %v", out.Imports)
+	}
+	if out.Module == nil || out.Module.Path != "github.com/test/repo" || out.Module.Version != "v1.2.3" {
+		t.Fatalf("unexpected Module: %+v", out.Module)
+	}
+	if len(out.Errors) != 1 || !strings.Contains(out.Errors[0], "undeclared name: Foo") {
+		t.Fatalf("expected the package's load error to be carried over, got %v", out.Errors)
+	}
+}
+
+func TestBuildPackageListFoldsTestVariants(t *testing.T) {
+	dep := &packages.Package{PkgPath: "github.com/test/repo/dep"}
+	testonly := &packages.Package{PkgPath: "github.com/test/repo/testonly"}
+
+	plain := &packages.Package{
+		ID:      "github.com/test/repo/p",
+		PkgPath: "github.com/test/repo/p",
+		Name:    "p",
+		Imports: map[string]*packages.Package{
+			"github.com/test/repo/dep": dep,
+		},
+	}
+	internalTest := &packages.Package{
+		ID:      "github.com/test/repo/p [github.com/test/repo/p.test]",
+		PkgPath: "github.com/test/repo/p",
+		Name:    "p",
+		Imports: map[string]*packages.Package{
+			"github.com/test/repo/dep":      dep,
+			"github.com/test/repo/testonly": testonly,
+		},
+	}
+	externalTest := &packages.Package{
+		ID:      "github.com/test/repo/p_test [github.com/test/repo/p.test]",
+		PkgPath: "github.com/test/repo/p_test",
+		Name:    "p_test",
+		Imports: map[string]*packages.Package{
+			"github.com/test/repo/p":        plain,
+			"github.com/test/repo/testonly": testonly,
+		},
+	}
+	testBinary := &packages.Package{
+		ID:      "github.com/test/repo/p.test",
+		PkgPath: "github.com/test/repo/p.test",
+		Name:    "main",
+	}
+
+	list := buildPackageList([]*packages.Package{plain, internalTest, externalTest, testBinary})
+
+	if len(list.Packages) != 1 {
+		t.Fatalf("expected the synthetic test binary to be discarded, got %d packages: %+v", len(list.Packages), list.Packages)
+	}
+
+	out := list.Packages[0]
+	if out.ImportPath != "github.com/test/repo/p" {
+		t.Fatalf("unexpected ImportPath: %v", out.ImportPath)
+	}
+	if len(out.TestImports) != 1 || out.TestImports[0] != "github.com/test/repo/testonly" {
+		t.Fatalf("unexpected TestImports: %v", out.TestImports)
+	}
+	if len(out.XTestImports) != 2 {
+		t.Fatalf("unexpected XTestImports: %v", out.XTestImports)
+	}
+}
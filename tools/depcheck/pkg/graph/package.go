@@ -0,0 +1,42 @@
+package graph
+
+// Package mirrors the subset of `go list -json` package output that the
+// dependency graph cares about. Callers typically build a PackageList by
+// unmarshalling the output of `go list -json ./...` (or a similar tool)
+// directly into this shape.
+type Package struct {
+	// Dir is the absolute directory the package lives in on disk.
+	Dir string
+	// ImportPath is the package's fully qualified import path.
+	ImportPath string
+	// Imports holds the import paths of every package imported by this
+	// package's non-test files.
+	Imports []string
+	// TestImports holds the import paths of packages imported only by
+	// this package's own (in-package) test files.
+	TestImports []string
+	// XTestImports holds the import paths of packages imported only by
+	// this package's external ("_test" package) test files.
+	XTestImports []string
+	// Module identifies the Go module this package belongs to. It is nil
+	// for packages loaded without module information.
+	Module *Module
+	// Errors holds any errors reported while loading this package (e.g.
+	// from LoadPackages), rather than aborting the whole load.
+	Errors []string
+}
+
+// Module identifies the Go module a package belongs to.
+type Module struct {
+	Path    string
+	Version string
+	// Main reports whether this is the main module of the build (as
+	// opposed to one of its dependencies).
+	Main bool
+}
+
+// PackageList is the top-level container for a set of packages, e.g. the
+// decoded result of `go list -json ./...`.
+type PackageList struct {
+	Packages []Package
+}
@@ -183,24 +183,23 @@ func TestPackagesWithInvalidPathsAreOmitted(t *testing.T) {
 		Packages: []Package{
 			{
 				Dir:        "/path/to/github.com/test/repo/invalid",
-				ImportPath: "invalid/import/path1",
-				Imports: []string{
-					"fmt",
-					"invalid.import.path2",
-					"invalid.import.path3",
-				},
+				ImportPath: "",
 			},
 			{
 				Dir:        "/path/to/github.com/test/repo/invalid",
-				ImportPath: "invalid.import.path2",
-				Imports: []string{
-					"net",
-					"encoding/json",
-				},
+				ImportPath: "/leading/slash",
 			},
 			{
 				Dir:        "/path/to/github.com/test/repo/invalid",
-				ImportPath: "invalid3",
+				ImportPath: "trailing/slash/",
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/invalid",
+				ImportPath: "double//slash",
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/invalid",
+				ImportPath: "has a space",
 			},
 		},
 	}
@@ -215,6 +214,30 @@ func TestPackagesWithInvalidPathsAreOmitted(t *testing.T) {
 	}
 }
 
+// TestPackagesWithMismatchedDirAreNotOmitted guards against reintroducing a
+// Dir-must-mirror-ImportPath heuristic: in module mode Dir routinely
+// diverges from ImportPath, e.g. a module cache entry carries an "@version"
+// segment that a plain suffix match would never see.
+func TestPackagesWithMismatchedDirAreNotOmitted(t *testing.T) {
+	pkgList := &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/home/user/go/pkg/mod/github.com/foo/bar@v1.2.3/baz",
+				ImportPath: "github.com/foo/bar/baz",
+			},
+		},
+	}
+
+	g, err := BuildGraph(pkgList, nil, []string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, exists := g.NodeByName("github.com/foo/bar/baz"); !exists {
+		t.Fatalf("expected a node for a module-cache package despite Dir not mirroring ImportPath")
+	}
+}
+
 func TestLabelNamesForVendoredNodes(t *testing.T) {
 	pkgList := &PackageList{
 		Packages: []Package{
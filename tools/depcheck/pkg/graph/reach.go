@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReachMap computes, for every node in the graph (or only for the graph's
+// declared root packages if mains is true), the set of import paths
+// reachable from it, split into two deduplicated, sorted slices: those
+// that live inside the repo root prefix (internal) and those that live
+// outside it (external).
+//
+// The repo root prefix is derived from a declared root package (or, if
+// none were declared, from an arbitrary node in the graph), using the
+// conventional "host/org/repo" shape of a Go import path: its first three
+// path segments.
+//
+// tests controls whether edges that exist only because of test-file
+// imports participate in the traversal.
+func (g *Graph) ReachMap(mains, tests bool) (internal, external map[string][]string, err error) {
+	if mains && len(g.roots) == 0 {
+		return nil, nil, fmt.Errorf("no root packages declared for this graph")
+	}
+
+	var rootPrefix string
+	switch {
+	case len(g.roots) > 0:
+		rootPrefix = repoPrefix(g.roots[0])
+	case len(g.order) > 0:
+		rootPrefix = repoPrefix(g.order[0])
+	}
+
+	wm := make(map[string]struct{ in, ex map[string]bool }, len(g.order))
+	for _, name := range g.order {
+		wm[name] = struct{ in, ex map[string]bool }{in: make(map[string]bool), ex: make(map[string]bool)}
+	}
+
+	// Expand each node's workmap entry against its direct successors'
+	// entries until nothing changes. This is a fixed-point pass rather
+	// than a plain DFS so that import cycles, which would otherwise
+	// never "finish", still converge on a correct answer.
+	for changed := true; changed; {
+		changed = false
+		for _, name := range g.order {
+			entry := wm[name]
+			for dep := range g.outEdges(name, tests) {
+				var bucket map[string]bool
+				if isInternal(dep, rootPrefix) {
+					bucket = entry.in
+				} else {
+					bucket = entry.ex
+				}
+				if !bucket[dep] {
+					bucket[dep] = true
+					changed = true
+				}
+
+				depEntry, ok := wm[dep]
+				if !ok {
+					continue
+				}
+				for d := range depEntry.in {
+					if !entry.in[d] {
+						entry.in[d] = true
+						changed = true
+					}
+				}
+				for d := range depEntry.ex {
+					if !entry.ex[d] {
+						entry.ex[d] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	names := g.order
+	if mains {
+		names = g.roots
+	}
+
+	internal = make(map[string][]string, len(names))
+	external = make(map[string][]string, len(names))
+	for _, name := range names {
+		entry, ok := wm[name]
+		if !ok {
+			continue
+		}
+		internal[name] = sortedKeys(entry.in)
+		external[name] = sortedKeys(entry.ex)
+	}
+
+	return internal, external, nil
+}
+
+// outEdges returns the names directly imported by name, optionally
+// including edges that only exist because of test-file imports.
+func (g *Graph) outEdges(name string, includeTests bool) map[string]struct{} {
+	out := make(map[string]struct{}, len(g.out[name]))
+	for to, kind := range g.out[name] {
+		if kind == ImportNormal || includeTests {
+			out[to] = struct{}{}
+		}
+	}
+	return out
+}
+
+// repoPrefix derives a repo root prefix from a representative import
+// path, using the conventional "host/org/repo" shape of a Go import path:
+// its first three "/"-separated segments.
+func repoPrefix(path string) string {
+	segs := strings.SplitN(path, "/", 4)
+	if len(segs) < 3 {
+		return path
+	}
+	return strings.Join(segs[:3], "/")
+}
+
+// isInternal reports whether importPath lives inside rootPrefix, matching
+// on whole path segments so that e.g. "github.com/test/repository" isn't
+// wrongly treated as internal to "github.com/test/repo".
+func isInternal(importPath, rootPrefix string) bool {
+	if rootPrefix == "" || !strings.HasPrefix(importPath, rootPrefix) {
+		return false
+	}
+	return len(importPath) == len(rootPrefix) || importPath[len(rootPrefix)] == '/'
+}
+
+// sortedKeys returns the keys of m as a sorted slice.
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
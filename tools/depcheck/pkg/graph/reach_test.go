@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func reachFixture() *PackageList {
+	return &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/path/to/github.com/test/repo/root",
+				ImportPath: "github.com/test/repo/root",
+				Imports:    []string{"github.com/test/repo/a"},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/a",
+				ImportPath: "github.com/test/repo/a",
+				Imports: []string{
+					"github.com/test/repo/root",
+					"github.com/other/dep",
+				},
+			},
+			{
+				Dir:        "/path/to/github.com/other/dep",
+				ImportPath: "github.com/other/dep",
+				Imports:    []string{},
+			},
+		},
+	}
+}
+
+func TestReachMapSplitsInternalAndExternalAcrossCycles(t *testing.T) {
+	g, err := BuildGraph(reachFixture(), []string{"github.com/test/repo/root"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	internal, external, err := g.ReachMap(false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantInternal := []string{"github.com/test/repo/a", "github.com/test/repo/root"}
+	if got := internal["github.com/test/repo/root"]; !reflect.DeepEqual(got, wantInternal) {
+		t.Fatalf("unexpected internal reach for root: got %v, want %v", got, wantInternal)
+	}
+
+	wantExternal := []string{"github.com/other/dep"}
+	if got := external["github.com/test/repo/root"]; !reflect.DeepEqual(got, wantExternal) {
+		t.Fatalf("unexpected external reach for root: got %v, want %v", got, wantExternal)
+	}
+
+	if got := external["github.com/other/dep"]; len(got) != 0 {
+		t.Fatalf("expected leaf package to have no external reach, got %v", got)
+	}
+}
+
+func TestReachMapMainsRestrictsToRoots(t *testing.T) {
+	g, err := BuildGraph(reachFixture(), []string{"github.com/test/repo/root"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	internal, _, err := g.ReachMap(true, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(internal) != 1 {
+		t.Fatalf("expected reach map restricted to 1 root, got %v entries", len(internal))
+	}
+	if _, ok := internal["github.com/test/repo/root"]; !ok {
+		t.Fatalf("expected reach map to contain the declared root")
+	}
+}
+
+func TestReachMapErrorsWithoutDeclaredRoots(t *testing.T) {
+	g, err := BuildGraph(reachFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, _, err := g.ReachMap(true, false); err == nil {
+		t.Fatalf("expected error when mains=true but no roots were declared")
+	}
+}
@@ -0,0 +1,137 @@
+package graph
+
+import "fmt"
+
+// StronglyConnectedComponents returns the graph's strongly connected
+// components, computed with Tarjan's algorithm. Each component is a slice
+// of the nodes it contains; a node with no cycle through it forms its own
+// single-element component.
+func (g *Graph) StronglyConnectedComponents() [][]*Node {
+	t := &tarjanState{
+		g:       g,
+		index:   make(map[string]int, len(g.order)),
+		lowlink: make(map[string]int, len(g.order)),
+		onStack: make(map[string]bool, len(g.order)),
+	}
+	for _, name := range g.order {
+		if _, visited := t.index[name]; !visited {
+			t.strongConnect(name)
+		}
+	}
+	return t.sccs
+}
+
+// Cycles returns the strongly connected components that represent an
+// actual import cycle: components with more than one member, plus any
+// single-node component whose package directly imports itself.
+func (g *Graph) Cycles() [][]*Node {
+	var cycles [][]*Node
+	for _, scc := range g.StronglyConnectedComponents() {
+		if len(scc) > 1 {
+			cycles = append(cycles, scc)
+			continue
+		}
+
+		name := scc[0].UniqueName
+		if _, selfLoop := g.out[name][name]; selfLoop {
+			cycles = append(cycles, scc)
+		}
+	}
+	return cycles
+}
+
+// Condensation returns the DAG obtained by collapsing each strongly
+// connected component of g into a single synthetic node. A component with
+// more than one member becomes a node labelled "cycle(n)" (n being the
+// number of members it collapses; its unique name is "cycle#i", i being
+// the component's position in StronglyConnectedComponents), exposing
+// those members through Node.Members; a component with a single member
+// passes through unchanged.
+func (g *Graph) Condensation() *Graph {
+	sccs := g.StronglyConnectedComponents()
+
+	componentOf := make(map[string]string, len(g.order))
+	cg := newGraph()
+
+	for i, scc := range sccs {
+		if len(scc) == 1 {
+			n := scc[0]
+			componentOf[n.UniqueName] = n.UniqueName
+			cg.addNode(&Node{UniqueName: n.UniqueName, LabelName: n.LabelName})
+			continue
+		}
+
+		id := fmt.Sprintf("cycle#%d", i)
+		for _, n := range scc {
+			componentOf[n.UniqueName] = id
+		}
+		cg.addNode(&Node{
+			UniqueName: id,
+			LabelName:  fmt.Sprintf("cycle(%d)", len(scc)),
+			Members:    scc,
+		})
+	}
+
+	for _, from := range g.order {
+		fromComponent := componentOf[from]
+		for to, kind := range g.out[from] {
+			toComponent := componentOf[to]
+			if fromComponent == toComponent {
+				continue
+			}
+			cg.addEdge(fromComponent, toComponent, kind)
+		}
+	}
+
+	return cg
+}
+
+// tarjanState holds the working state for a single run of Tarjan's
+// strongly-connected-components algorithm over a Graph.
+type tarjanState struct {
+	g       *Graph
+	counter int
+	index   map[string]int
+	lowlink map[string]int
+	onStack map[string]bool
+	stack   []string
+	sccs    [][]*Node
+}
+
+func (t *tarjanState) strongConnect(v string) {
+	t.index[v] = t.counter
+	t.lowlink[v] = t.counter
+	t.counter++
+	t.stack = append(t.stack, v)
+	t.onStack[v] = true
+
+	for w := range t.g.out[v] {
+		if _, visited := t.index[w]; !visited {
+			t.strongConnect(w)
+			if t.lowlink[w] < t.lowlink[v] {
+				t.lowlink[v] = t.lowlink[w]
+			}
+		} else if t.onStack[w] {
+			if t.index[w] < t.lowlink[v] {
+				t.lowlink[v] = t.index[w]
+			}
+		}
+	}
+
+	if t.lowlink[v] != t.index[v] {
+		return
+	}
+
+	var scc []*Node
+	for {
+		n := len(t.stack) - 1
+		w := t.stack[n]
+		t.stack = t.stack[:n]
+		t.onStack[w] = false
+		scc = append(scc, t.g.nodes[w])
+		if w == v {
+			break
+		}
+	}
+	t.sccs = append(t.sccs, scc)
+}
@@ -0,0 +1,141 @@
+package graph
+
+import "testing"
+
+func sccFixture() *PackageList {
+	return &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/path/to/github.com/test/repo/root",
+				ImportPath: "github.com/test/repo/root",
+				Imports:    []string{"github.com/test/repo/a"},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/a",
+				ImportPath: "github.com/test/repo/a",
+				Imports: []string{
+					"github.com/test/repo/b",
+					"github.com/test/repo/c",
+				},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/b",
+				ImportPath: "github.com/test/repo/b",
+				Imports:    []string{"github.com/test/repo/a"},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/c",
+				ImportPath: "github.com/test/repo/c",
+				Imports:    []string{"github.com/test/repo/c"},
+			},
+		},
+	}
+}
+
+func findComponent(sccs [][]*Node, name string) []*Node {
+	for _, scc := range sccs {
+		for _, n := range scc {
+			if n.UniqueName == name {
+				return scc
+			}
+		}
+	}
+	return nil
+}
+
+func TestStronglyConnectedComponentsGroupsCycle(t *testing.T) {
+	g, err := BuildGraph(sccFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sccs := g.StronglyConnectedComponents()
+
+	abComponent := findComponent(sccs, "github.com/test/repo/a")
+	if len(abComponent) != 2 {
+		t.Fatalf("expected a and b to form a single 2-node component, got %v", abComponent)
+	}
+
+	rootComponent := findComponent(sccs, "github.com/test/repo/root")
+	if len(rootComponent) != 1 {
+		t.Fatalf("expected root to form its own component, got %v", rootComponent)
+	}
+}
+
+func TestCyclesIncludesSelfLoopsAndMultiNodeCycles(t *testing.T) {
+	g, err := BuildGraph(sccFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cycles := g.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("expected 2 cycles (a<->b and the c self-loop), got %v", cycles)
+	}
+
+	var sawPair, sawSelfLoop bool
+	for _, cycle := range cycles {
+		switch len(cycle) {
+		case 2:
+			sawPair = true
+		case 1:
+			if cycle[0].UniqueName == "github.com/test/repo/c" {
+				sawSelfLoop = true
+			}
+		}
+	}
+	if !sawPair {
+		t.Fatalf("expected the a<->b cycle to be reported")
+	}
+	if !sawSelfLoop {
+		t.Fatalf("expected the c self-loop to be reported")
+	}
+
+	for _, cycle := range cycles {
+		if len(cycle) == 1 && cycle[0].UniqueName == "github.com/test/repo/root" {
+			t.Fatalf("did not expect acyclic root to be reported as a cycle")
+		}
+	}
+}
+
+func TestCondensationCollapsesCyclesAndPreservesOutsideEdges(t *testing.T) {
+	g, err := BuildGraph(sccFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cg := g.Condensation()
+
+	var cycleNode *Node
+	for _, n := range cg.Nodes() {
+		node := n.(*Node)
+		if node.LabelName == "cycle(2)" {
+			cycleNode = node
+		}
+	}
+	if cycleNode == nil {
+		t.Fatalf("expected a condensed node labeled cycle(2)")
+	}
+	if len(cycleNode.Members) != 2 {
+		t.Fatalf("expected the condensed node to list its 2 members, got %v", cycleNode.Members)
+	}
+
+	root, exists := cg.NodeByName("github.com/test/repo/root")
+	if !exists {
+		t.Fatalf("expected the acyclic root node to pass through unchanged")
+	}
+	if !cg.HasEdgeFromTo(root, cycleNode) {
+		t.Fatalf("expected root to edge into the condensed cycle node")
+	}
+
+	c, exists := cg.NodeByName("github.com/test/repo/c")
+	if !exists {
+		t.Fatalf("expected the self-looping c node to pass through unchanged")
+	}
+	if !cg.HasEdgeFromTo(cycleNode, c) {
+		t.Fatalf("expected the condensed cycle node to edge out to c")
+	}
+	if cg.HasEdgeFromTo(c, c) {
+		t.Fatalf("did not expect the condensation to retain c's self-loop")
+	}
+}
@@ -0,0 +1,75 @@
+package graph
+
+import "strings"
+
+// isHiddenDir reports whether dir contains a path segment that the go
+// tool itself treats as non-buildable: one beginning with "." or "_", or
+// one that is exactly "testdata".
+func isHiddenDir(dir string) bool {
+	for _, seg := range strings.Split(dir, "/") {
+		if seg == "" {
+			continue
+		}
+		if seg == "testdata" || strings.HasPrefix(seg, ".") || strings.HasPrefix(seg, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// TrimHidden returns a copy of pl with packages living under hidden
+// directories (testdata/, and dot- or underscore-prefixed directories)
+// removed, unless the package is transitively imported by a package that
+// isn't itself hidden.
+//
+// Without this, a PackageList built over a repo with large testdata/
+// fixtures ends up with a graph node per fixture package, even though the
+// go tool would never treat those directories as importable.
+func (pl *PackageList) TrimHidden() *PackageList {
+	byImport := make(map[string]Package, len(pl.Packages))
+	hidden := make(map[string]bool, len(pl.Packages))
+	var normalRoots []string
+
+	for _, pkg := range pl.Packages {
+		byImport[pkg.ImportPath] = pkg
+		if isHiddenDir(pkg.Dir) {
+			hidden[pkg.ImportPath] = true
+		} else {
+			normalRoots = append(normalRoots, pkg.ImportPath)
+		}
+	}
+
+	reachableHidden := make(map[string]bool)
+	visited := make(map[string]bool)
+	stack := append([]string(nil), normalRoots...)
+
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		pkg, ok := byImport[name]
+		if !ok {
+			continue
+		}
+		for _, dep := range pkg.Imports {
+			if hidden[dep] {
+				reachableHidden[dep] = true
+			}
+			stack = append(stack, dep)
+		}
+	}
+
+	kept := make([]Package, 0, len(pl.Packages))
+	for _, pkg := range pl.Packages {
+		if hidden[pkg.ImportPath] && !reachableHidden[pkg.ImportPath] {
+			continue
+		}
+		kept = append(kept, pkg)
+	}
+
+	return &PackageList{Packages: kept}
+}
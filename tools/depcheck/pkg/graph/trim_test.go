@@ -0,0 +1,92 @@
+package graph
+
+import "testing"
+
+func TestTrimHiddenDropsUnreachableHiddenPackages(t *testing.T) {
+	pl := &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/path/to/github.com/test/repo/root",
+				ImportPath: "github.com/test/repo/root",
+				Imports: []string{
+					"github.com/test/repo/testdata/fixture_one",
+				},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/testdata/fixture_one",
+				ImportPath: "github.com/test/repo/testdata/fixture_one",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/testdata/fixture_two",
+				ImportPath: "github.com/test/repo/testdata/fixture_two",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/.hidden/dotdir_pkg",
+				ImportPath: "github.com/test/repo/.hidden/dotdir_pkg",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/_hidden/underscore_pkg",
+				ImportPath: "github.com/test/repo/_hidden/underscore_pkg",
+				Imports:    []string{},
+			},
+		},
+	}
+
+	trimmed := pl.TrimHidden()
+
+	byImport := make(map[string]bool)
+	for _, pkg := range trimmed.Packages {
+		byImport[pkg.ImportPath] = true
+	}
+
+	if !byImport["github.com/test/repo/root"] {
+		t.Fatalf("expected non-hidden root package to survive trimming")
+	}
+	if !byImport["github.com/test/repo/testdata/fixture_one"] {
+		t.Fatalf("expected reachable testdata package to survive trimming")
+	}
+	if byImport["github.com/test/repo/testdata/fixture_two"] {
+		t.Fatalf("expected unreachable testdata package to be trimmed")
+	}
+	if byImport["github.com/test/repo/.hidden/dotdir_pkg"] {
+		t.Fatalf("expected unreachable dot-dir package to be trimmed")
+	}
+	if byImport["github.com/test/repo/_hidden/underscore_pkg"] {
+		t.Fatalf("expected unreachable underscore-dir package to be trimmed")
+	}
+}
+
+func TestTrimHiddenKeepsTransitivelyReachableHidden(t *testing.T) {
+	pl := &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/path/to/github.com/test/repo/root",
+				ImportPath: "github.com/test/repo/root",
+				Imports: []string{
+					"github.com/test/repo/testdata/fixture_one",
+				},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/testdata/fixture_one",
+				ImportPath: "github.com/test/repo/testdata/fixture_one",
+				Imports: []string{
+					"github.com/test/repo/testdata/fixture_nested",
+				},
+			},
+			{
+				Dir:        "/path/to/github.com/test/repo/testdata/fixture_nested",
+				ImportPath: "github.com/test/repo/testdata/fixture_nested",
+				Imports:    []string{},
+			},
+		},
+	}
+
+	trimmed := pl.TrimHidden()
+
+	if len(trimmed.Packages) != 3 {
+		t.Fatalf("expected all 3 packages to survive via transitive reachability, got %v", len(trimmed.Packages))
+	}
+}
@@ -0,0 +1,30 @@
+package graph
+
+import "path"
+
+// resolveImport applies Go's vendor resolution rules to an import found in
+// a package living at dir: starting at dir and walking up through each
+// ancestor directory, the first "<ancestor>/vendor/importPath" that
+// corresponds to a known package wins (so the nearest enclosing vendor
+// directory always takes precedence over one further up the tree). If no
+// such package is found, importPath is returned unresolved.
+//
+// The walk climbs all the way to the filesystem root rather than stopping
+// at the module or GOPATH root; in practice this is harmless because byDir
+// only contains directories of packages we actually loaded, so ancestors
+// outside the module never match.
+//
+// byDir maps a package's directory to its import path.
+func resolveImport(byDir map[string]string, dir, importPath string) string {
+	for d := dir; ; {
+		if resolved, ok := byDir[path.Join(d, "vendor", importPath)]; ok {
+			return resolved
+		}
+
+		parent := path.Dir(d)
+		if parent == d {
+			return importPath
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,97 @@
+package graph
+
+import "testing"
+
+// vendorFixture lays out:
+//
+//	vend
+//	vend/x
+//	vend/x/y
+//	vend/vendor/p1
+//	vend/x/vendor/p
+//	vend/x/vendor/p2
+//
+// so that an import of "p1" from vend/x/y must climb two levels to find
+// vend/vendor/p1, while an import of "p2" from vend/x/y finds the nearer
+// vend/x/vendor/p2 without climbing any further.
+func vendorFixture() *PackageList {
+	return &PackageList{
+		Packages: []Package{
+			{
+				Dir:        "/src/vend",
+				ImportPath: "vend",
+				Imports:    []string{"vend/x"},
+			},
+			{
+				Dir:        "/src/vend/x",
+				ImportPath: "vend/x",
+				Imports:    []string{"p"},
+			},
+			{
+				Dir:        "/src/vend/x/y",
+				ImportPath: "vend/x/y",
+				Imports:    []string{"p1", "p2"},
+			},
+			{
+				Dir:        "/src/vend/vendor/p1",
+				ImportPath: "vend/vendor/p1",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/src/vend/x/vendor/p",
+				ImportPath: "vend/x/vendor/p",
+				Imports:    []string{},
+			},
+			{
+				Dir:        "/src/vend/x/vendor/p2",
+				ImportPath: "vend/x/vendor/p2",
+				Imports:    []string{},
+			},
+		},
+	}
+}
+
+func TestBuildGraphResolvesNearestVendorDirectory(t *testing.T) {
+	g, err := BuildGraph(vendorFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	x, _ := g.NodeByName("vend/x")
+	xVendorP, exists := g.NodeByName("vend/x/vendor/p")
+	if !exists {
+		t.Fatalf("expected node for vend/x/vendor/p to exist")
+	}
+	if !g.HasEdgeFromTo(x, xVendorP) {
+		t.Fatalf("expected vend/x to edge to vend/x/vendor/p, not a vendor dir further up the tree")
+	}
+
+	if _, exists := g.NodeByName("vend/vendor/p"); exists {
+		t.Fatalf("did not expect a node for vend/vendor/p (no such package in the fixture)")
+	}
+}
+
+func TestBuildGraphClimbsAncestorsForVendorResolution(t *testing.T) {
+	g, err := BuildGraph(vendorFixture(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	xy, _ := g.NodeByName("vend/x/y")
+
+	p1, exists := g.NodeByName("vend/vendor/p1")
+	if !exists {
+		t.Fatalf("expected node for vend/vendor/p1 to exist")
+	}
+	if !g.HasEdgeFromTo(xy, p1) {
+		t.Fatalf("expected vend/x/y to climb up to vend/vendor/p1 for import %q", "p1")
+	}
+
+	p2, exists := g.NodeByName("vend/x/vendor/p2")
+	if !exists {
+		t.Fatalf("expected node for vend/x/vendor/p2 to exist")
+	}
+	if !g.HasEdgeFromTo(xy, p2) {
+		t.Fatalf("expected vend/x/y to resolve import %q to the nearer vend/x/vendor/p2", "p2")
+	}
+}